@@ -0,0 +1,242 @@
+package go_ph0n3
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ============================================================================
+// dtmfWindowSeconds Length of the analysis window the Goertzel filters run
+// over: the classic 205-sample window of the Goertzel DTMF algorithm,
+// referenced to its usual 8kHz sample rate (~25.6ms) and scaled to whatever
+// sample rate the Decoder is configured with. Frequency resolution is
+// 1/dtmfWindowSeconds regardless of sample rate, so this length is what
+// lets adjacent DTMF rows/columns (73Hz apart at the closest) be told
+// apart; a much shorter window can't resolve them no matter the rate.
+const dtmfWindowSeconds = 205.0 / 8000.0
+
+// dtmfKeys Maps a (row, col) Goertzel bin pair to the key it represents,
+// indexed the same way fqMapRows/fqMapCols are.
+var dtmfKeys = [4][4]Ph0n3Key{
+	{Key1, Key2, Key3, KeyA},
+	{Key4, Key5, Key6, KeyB},
+	{Key7, Key8, Key9, KeyC},
+	{KeyStar, Key0, KeyHash, KeyD},
+}
+
+// DecoderOptions Tunes how forgiving a Decoder is about what counts as a
+// key press.
+type DecoderOptions struct {
+	// ToneDuration Nominal length of a press: a pair of tones is accepted
+	// once it has persisted for half of it. Defaults to
+	// Ph0n3Options.ToneDuration so a Decoder can recover what a Ph0n3 on
+	// default options dialed.
+	ToneDuration time.Duration
+	// SpaceDuration Nominal gap between two presses: the next one is only
+	// emitted after at least half of it has been observed as silence.
+	// Defaults to Ph0n3Options.SpaceDuration.
+	SpaceDuration time.Duration
+	// Threshold Minimum Goertzel power a bin must reach to count as
+	// present at all.
+	Threshold float64
+	// Twist Minimum ratio the strongest bin in a row/column group must beat
+	// the next-strongest bin of that group by to be accepted; guards against harmonics and
+	// noise being mistaken for a tone.
+	Twist float64
+}
+
+// DefaultDecoderOptions the default values.
+var DefaultDecoderOptions = DecoderOptions{
+	ToneDuration:  DefaultPh0n3Options.ToneDuration,
+	SpaceDuration: DefaultPh0n3Options.SpaceDuration,
+	Threshold:     1e5,
+	Twist:         4,
+}
+
+// ============================================================================
+// Decoder Turns a PCM stream back into the Ph0n3Key presses that produced
+// it, using a bank of Goertzel filters tuned to the 8 DTMF frequencies.
+type Decoder struct {
+	Options DecoderOptions
+
+	sampleRate int
+	channels   int
+	windowSize int
+	windowDur  time.Duration
+	rowCoeffs  [4]float64
+	colCoeffs  [4]float64
+
+	accum []int16
+
+	keys chan Ph0n3Key
+
+	hasTone      bool
+	tone         Ph0n3Key
+	toneDuration time.Duration
+	silence      time.Duration
+	canEmit      bool
+}
+
+// NewDecoder Returns a Decoder ready to consume PCM at sampleRate with the
+// given channel count.
+func NewDecoder(sampleRate, channels int) *Decoder {
+	windowSize := int(math.Round(float64(sampleRate) * dtmfWindowSeconds))
+	if windowSize < 1 {
+		windowSize = 1
+	}
+
+	d := &Decoder{
+		Options:    DefaultDecoderOptions,
+		sampleRate: sampleRate,
+		channels:   channels,
+		windowSize: windowSize,
+		windowDur:  time.Duration(float64(windowSize) / float64(sampleRate) * float64(time.Second)),
+		keys:       make(chan Ph0n3Key, 16),
+		canEmit:    true,
+	}
+
+	for i, f := range fqMapRows {
+		d.rowCoeffs[i] = goertzelCoeff(f, windowSize, sampleRate)
+	}
+	for i, f := range fqMapCols {
+		d.colCoeffs[i] = goertzelCoeff(f, windowSize, sampleRate)
+	}
+
+	return d
+}
+
+// goertzelCoeff Precomputes the Goertzel recurrence coefficient for freq
+// over a window of n samples at sampleRate: k = round(n*freq/sampleRate),
+// omega = 2*pi*k/n, coeff = 2*cos(omega).
+func goertzelCoeff(freq float64, n, sampleRate int) float64 {
+	k := math.Round(float64(n) * freq / float64(sampleRate))
+	omega := 2 * math.Pi * k / float64(n)
+	return 2 * math.Cos(omega)
+}
+
+// goertzelPower Runs the Goertzel recurrence for coeff over samples and
+// returns the resulting bin power.
+func goertzelPower(samples []int16, coeff float64) float64 {
+	var s1, s2 float64
+	for _, x := range samples {
+		s := float64(x) + coeff*s1 - s2
+		s2 = s1
+		s1 = s
+	}
+	return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+// dominantBin Picks the strongest of powers, requiring it to clear
+// threshold and to beat the next-strongest bin by at least twist.
+func dominantBin(powers []float64, threshold, twist float64) (int, bool) {
+	best, second := -1, -1
+	for i, p := range powers {
+		switch {
+		case best == -1 || p > powers[best]:
+			second = best
+			best = i
+		case second == -1 || p > powers[second]:
+			second = i
+		}
+	}
+
+	if best == -1 || powers[best] < threshold {
+		return 0, false
+	}
+	if second != -1 && powers[best] < twist*powers[second] {
+		return 0, false
+	}
+	return best, true
+}
+
+// Write Feeds interleaved 16-bit PCM samples, at the sample rate and
+// channel count Decoder was constructed with, into the decoder. Channels
+// are downmixed to mono before analysis.
+func (d *Decoder) Write(p []byte) (int, error) {
+	if len(p)%2 != 0 {
+		return 0, errors.New("go-ph0n3: odd byte count, PCM samples must be 16-bit")
+	}
+
+	samples := bytesToInt16(p)
+	for i := 0; i < len(samples); i += d.channels {
+		end := i + d.channels
+		if end > len(samples) {
+			end = len(samples)
+		}
+
+		var sum int32
+		for _, s := range samples[i:end] {
+			sum += int32(s)
+		}
+		d.accum = append(d.accum, int16(sum/int32(end-i)))
+	}
+
+	for len(d.accum) >= d.windowSize {
+		d.processWindow(d.accum[:d.windowSize])
+		d.accum = d.accum[d.windowSize:]
+	}
+
+	return len(p), nil
+}
+
+// processWindow Classifies one window of mono samples and advances the
+// press/silence state machine with the result.
+func (d *Decoder) processWindow(window []int16) {
+	var rowPowers, colPowers [4]float64
+	for i, coeff := range d.rowCoeffs {
+		rowPowers[i] = goertzelPower(window, coeff)
+	}
+	for i, coeff := range d.colCoeffs {
+		colPowers[i] = goertzelPower(window, coeff)
+	}
+
+	row, rowOK := dominantBin(rowPowers[:], d.Options.Threshold, d.Options.Twist)
+	col, colOK := dominantBin(colPowers[:], d.Options.Threshold, d.Options.Twist)
+
+	if !rowOK || !colOK {
+		d.advance(false, 0)
+		return
+	}
+	d.advance(true, dtmfKeys[row][col])
+}
+
+// advance Runs the rising-edge/debounce logic: a key only reaches Keys()
+// once its tones have persisted for ToneDuration/2 and at least
+// SpaceDuration/2 of silence separates it from whatever was emitted before.
+// The /2 tolerance, same as the one ToneDuration gets below, matters because
+// silence is only ever observed in whole windowDur-sized chunks: the window
+// right after a tone ends is almost always part-silence, part-onset of the
+// next one and gets classified as "detected", so accumulated silence rarely
+// reaches a full SpaceDuration even when the real gap is exactly that long.
+func (d *Decoder) advance(detected bool, key Ph0n3Key) {
+	if !detected {
+		d.hasTone = false
+		d.toneDuration = 0
+		d.silence += d.windowDur
+		if d.silence >= d.Options.SpaceDuration/2 {
+			d.canEmit = true
+		}
+		return
+	}
+
+	if d.hasTone && d.tone == key {
+		d.toneDuration += d.windowDur
+	} else {
+		d.hasTone = true
+		d.tone = key
+		d.toneDuration = d.windowDur
+	}
+	d.silence = 0
+
+	if d.canEmit && d.toneDuration >= d.Options.ToneDuration/2 {
+		d.keys <- key
+		d.canEmit = false
+	}
+}
+
+// Keys Returns the channel Ph0n3Key presses are emitted on as they're
+// decoded from the stream fed to Write.
+func (d *Decoder) Keys() <-chan Ph0n3Key {
+	return d.keys
+}
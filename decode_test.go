@@ -0,0 +1,70 @@
+package go_ph0n3_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	go_ph0n3 "github.com/umarquez/go-ph0n3"
+)
+
+// TestDecoderRoundTrip dials a multi-digit sequence into a WavFileSink, then
+// feeds the rendered file back into a Decoder and checks every digit comes
+// back out, not just the first one.
+func TestDecoderRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/dialed.wav"
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	const sampleRate = 8000
+	sink, err := go_ph0n3.NewWavFileSink(f, sampleRate, 1)
+	if err != nil {
+		t.Fatalf("NewWavFileSink: %v", err)
+	}
+
+	phone := go_ph0n3.NewPh0n3WithSink(nil, sink)
+	want := []go_ph0n3.Ph0n3Key{go_ph0n3.Key1, go_ph0n3.Key2, go_ph0n3.Key3}
+	if err := phone.Dial(want...); err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("sink.Close: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("f.Close: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(raw) < 44 {
+		t.Fatalf("rendered file too short to hold a WAVE header: %d bytes", len(raw))
+	}
+
+	dec := go_ph0n3.NewDecoder(sampleRate, 1)
+	if _, err := dec.Write(raw[44:]); err != nil {
+		t.Fatalf("dec.Write: %v", err)
+	}
+
+	var got []go_ph0n3.Ph0n3Key
+	for len(got) < len(want) {
+		select {
+		case k := <-dec.Keys():
+			got = append(got, k)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for keys, got %v so far", got)
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v keys, want %v", got, want)
+	}
+	for i, k := range want {
+		if got[i] != k {
+			t.Errorf("key %d: got %v, want %v", i, got[i], k)
+		}
+	}
+}
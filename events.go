@@ -0,0 +1,56 @@
+package go_ph0n3
+
+import "time"
+
+// ============================================================================
+// Ph0n3State Is where a Ph0n3 instance is in its call lifecycle.
+type Ph0n3State int
+
+const (
+	// StateIdle The line hasn't been opened yet.
+	StateIdle Ph0n3State = iota
+	// StateOpen The line is open: the dial tone (if any) is playing or has
+	// played, and Dial can be called.
+	StateOpen
+	// StateRinging No digits were dialed for long enough that the phone
+	// moved on to the ringing tone.
+	StateRinging
+	// StateBusy The ringing tone finished and the busy/reorder tone is
+	// playing before the call ends.
+	StateBusy
+	// StateClosed Hangup was called (directly, via context cancellation,
+	// or because the call ran its course); the line is done.
+	StateClosed
+)
+
+// Ph0n3Event Is something that happened during a call, delivered through
+// Ph0n3.Events(). Concrete types are KeyPressed, DialToneStarted, Ringing,
+// Busy and Closed.
+type Ph0n3Event interface {
+	ph0n3Event()
+}
+
+// KeyPressed A key was dialed.
+type KeyPressed struct {
+	Key Ph0n3Key
+	At  time.Time
+}
+
+// DialToneStarted The line was opened and its dial tone started playing.
+type DialToneStarted struct{}
+
+// Ringing No digits were dialed for long enough that the ringback tone
+// started playing.
+type Ringing struct{}
+
+// Busy The ringing finished and the busy/reorder tone started playing.
+type Busy struct{}
+
+// Closed The call ended and the line was closed.
+type Closed struct{}
+
+func (KeyPressed) ph0n3Event()      {}
+func (DialToneStarted) ph0n3Event() {}
+func (Ringing) ph0n3Event()         {}
+func (Busy) ph0n3Event()            {}
+func (Closed) ph0n3Event()          {}
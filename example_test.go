@@ -1,9 +1,35 @@
 package go_ph0n3_test
 
-import "github.com/umarquez/go-ph0n3"
+import (
+	"context"
+	"os"
+
+	"github.com/umarquez/go-ph0n3"
+)
 
 func ExamplePh0n3() {
-	phone := go_ph0n3.NewPh0n3(nil).Open()
+	phone := go_ph0n3.NewPh0n3(nil).Open(context.Background())
+	_ = phone.DialString("13243546")
+	<-phone.Done()
+}
+
+// ExamplePh0n3_fileRendered shows rendering a dialed sequence to a WAV file
+// instead of playing it live, useful for tests, CI, or any environment
+// without an audio device.
+func ExamplePh0n3_fileRendered() {
+	f, err := os.Create("dialed.wav")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	sink, err := go_ph0n3.NewWavFileSink(f, 44100, 1)
+	if err != nil {
+		panic(err)
+	}
+	defer sink.Close()
+
+	phone := go_ph0n3.NewPh0n3WithSink(nil, sink).Open(context.Background())
 	_ = phone.DialString("13243546")
-	<-phone.Close
+	<-phone.Done()
 }
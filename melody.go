@@ -0,0 +1,183 @@
+package go_ph0n3
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// MelodyNote One note (or rest, when Freq is 0) in a parsed melody.
+type MelodyNote struct {
+	Freq     float64
+	Duration time.Duration
+}
+
+// semitones Maps an RTTTL note letter (optionally sharped) to its semitone
+// offset from C, c=0..b=11.
+var semitones = map[string]int{
+	"c": 0, "c#": 1, "d": 2, "d#": 3, "e": 4, "f": 5, "f#": 6,
+	"g": 7, "g#": 8, "a": 9, "a#": 10, "b": 11,
+}
+
+// rtttlNote Matches one "[duration]pitch[#][octave][.]" token, e.g. "8e5",
+// "4c#6", "2p", "g.".
+var rtttlNote = regexp.MustCompile(`(?i)^(\d*)([a-g]#?|p)(\d*)(\.?)$`)
+
+// ParseRTTTL Parses an RTTTL ringtone string (`name:d=4,o=6,b=63:8e5,8e5,
+// ...`) into the sequence of notes it plays. Durations come from
+// 60000/bpm*(4/d) ms, a dotted note (".") is 1.5x its duration, and "p" is a
+// rest. d=4,o=6,b=63 are the RTTTL spec's own defaults, used when a field is
+// missing from the control section entirely.
+func ParseRTTTL(rtttl string) ([]MelodyNote, error) {
+	parts := strings.SplitN(rtttl, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("go-ph0n3: malformed RTTTL, expected name:defaults:notes")
+	}
+
+	duration, octave, bpm := 4, 6, 63
+	for _, field := range strings.Split(parts[1], ",") {
+		field = strings.TrimSpace(field)
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("go-ph0n3: invalid RTTTL default %q: %w", field, err)
+		}
+		switch strings.TrimSpace(kv[0]) {
+		case "d":
+			duration = v
+		case "o":
+			octave = v
+		case "b":
+			bpm = v
+		}
+	}
+
+	var notes []MelodyNote
+	for _, raw := range strings.Split(parts[2], ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		m := rtttlNote.FindStringSubmatch(raw)
+		if m == nil {
+			return nil, fmt.Errorf("go-ph0n3: invalid RTTTL note %q", raw)
+		}
+		durDigits, pitch, octDigits, dot := m[1], strings.ToLower(m[2]), m[3], m[4]
+
+		d := duration
+		if durDigits != "" {
+			d, _ = strconv.Atoi(durDigits)
+		}
+		ms := 60000.0 / float64(bpm) * (4.0 / float64(d))
+		if dot == "." {
+			ms *= 1.5
+		}
+
+		note := MelodyNote{Duration: time.Duration(ms * float64(time.Millisecond))}
+		if pitch != "p" {
+			o := octave
+			if octDigits != "" {
+				o, _ = strconv.Atoi(octDigits)
+			}
+			note.Freq = noteFrequency(pitch, o)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+// noteFrequency Returns the equal-tempered frequency of pitch (e.g. "c#")
+// in the given octave, referenced to A4 = 440Hz.
+func noteFrequency(pitch string, octave int) float64 {
+	n := octave*12 + semitones[pitch]
+	const a4 = 4*12 + 9
+	halfSteps := n - a4
+	return 440 * math.Pow(2, float64(halfSteps)/12)
+}
+
+// easterEggMelody Is the little tune endingCall plays when "55555" was
+// dialed; it predates RTTTL support in this package, so it's kept as raw
+// (freq, duration) pairs rather than round-tripped through a ringtone
+// string.
+var easterEggMelody = buildEasterEggMelody()
+
+func buildEasterEggMelody() []MelodyNote {
+	data := []float64{0.055, 233.8, 4, 311.13, 2, 369.99, 4, 415.3,
+		2, 440, 4, 466.6, 2, 440, 4, 415.3, 2, 369.99, 6, 233.8, 6, 277.18, 6, 311.13, 13}
+
+	var notes []MelodyNote
+	var f, t float64
+	for i, v := range data {
+		if i == 0 {
+			t = v
+			continue
+		}
+		if (i+3)%2 == 1 {
+			notes = append(notes, MelodyNote{Freq: f, Duration: time.Duration(t * v * 1e9)})
+		} else {
+			f = v
+		}
+	}
+	return notes
+}
+
+// playNotes Sequences notes through the existing play path, rendering rests
+// (Freq == 0) as silence through playSilence instead of just sleeping past
+// them, so a file sink encodes the rest's duration too.
+func (phone *Ph0n3) playNotes(notes []MelodyNote) {
+	for _, n := range notes {
+		if n.Freq <= 0 {
+			phone.playSilence(n.Duration)
+			continue
+		}
+
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		go phone.play(ToneSegment{SumFreqs: []float64{n.Freq}}, n.Duration, wg)
+		wg.Wait()
+	}
+}
+
+// PlayMelody Parses rtttl and plays it through the phone's current sink.
+func (phone *Ph0n3) PlayMelody(rtttl string) error {
+	notes, err := ParseRTTTL(rtttl)
+	if err != nil {
+		return err
+	}
+	phone.playNotes(notes)
+	return nil
+}
+
+// SetRingback Replaces the ringing tone dialing() plays after a call is
+// placed with rtttl, instead of the active tone pack's "ring" cadence.
+func (phone *Ph0n3) SetRingback(rtttl string) error {
+	notes, err := ParseRTTTL(rtttl)
+	if err != nil {
+		return err
+	}
+
+	segments := make([]ToneSegment, len(notes))
+	for i, n := range notes {
+		if n.Freq > 0 {
+			segments[i] = ToneSegment{SumFreqs: []float64{n.Freq}, Duration: n.Duration}
+		} else {
+			segments[i] = ToneSegment{Duration: n.Duration}
+		}
+	}
+
+	phone.mu.Lock()
+	phone.ringback = segments
+	phone.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,90 @@
+package go_ph0n3_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	go_ph0n3 "github.com/umarquez/go-ph0n3"
+)
+
+// TestParseRTTTL covers the "name:defaults:notes" grammar: the d=/o=/b=
+// defaults (and their RTTTL-spec fallback of d=4,o=6,b=63 when a field is
+// missing), dotted durations, per-note overrides, and rests.
+func TestParseRTTTL(t *testing.T) {
+	notes, err := go_ph0n3.ParseRTTTL("test:d=4,o=5,b=60:4c,8p,4c6,4c.")
+	if err != nil {
+		t.Fatalf("ParseRTTTL: %v", err)
+	}
+	if len(notes) != 4 {
+		t.Fatalf("got %d notes, want 4: %+v", len(notes), notes)
+	}
+
+	// "4c" at o=5, b=60: a quarter note is 60000/60*(4/4) = 1000ms.
+	wantC5 := go_ph0n3.MelodyNote{Freq: noteFreqForTest(5, "c"), Duration: time.Second}
+	if notes[0] != wantC5 {
+		t.Errorf("note 0 = %+v, want %+v", notes[0], wantC5)
+	}
+
+	// "8p": a rest carries no frequency.
+	if notes[1].Freq != 0 {
+		t.Errorf("note 1 (rest) has Freq %v, want 0", notes[1].Freq)
+	}
+
+	// "4c6": explicit octave 6 overrides the o=5 default.
+	wantC6 := noteFreqForTest(6, "c")
+	if notes[2].Freq != wantC6 {
+		t.Errorf("note 2 freq = %v, want %v (c6)", notes[2].Freq, wantC6)
+	}
+
+	// "4c.": dotted quarter note is 1.5x a plain one.
+	wantDotted := time.Duration(1.5 * float64(time.Second))
+	if notes[3].Duration != wantDotted {
+		t.Errorf("note 3 (dotted) duration = %v, want %v", notes[3].Duration, wantDotted)
+	}
+}
+
+// TestParseRTTTLDefaults covers the spec's own control-section defaults
+// (d=4,o=6,b=63) kicking in when the control section omits a field entirely.
+func TestParseRTTTLDefaults(t *testing.T) {
+	notes, err := go_ph0n3.ParseRTTTL("test::c")
+	if err != nil {
+		t.Fatalf("ParseRTTTL: %v", err)
+	}
+	if len(notes) != 1 {
+		t.Fatalf("got %d notes, want 1: %+v", len(notes), notes)
+	}
+
+	// d=4,b=63 default: a quarter note is 60000/63*(4/4) ms.
+	bpm := 63.0
+	wantDuration := time.Duration(60000.0 / bpm * float64(time.Millisecond))
+	if notes[0].Duration != wantDuration {
+		t.Errorf("duration = %v, want %v", notes[0].Duration, wantDuration)
+	}
+	// o=6 default.
+	if want := noteFreqForTest(6, "c"); notes[0].Freq != want {
+		t.Errorf("freq = %v, want %v (c6)", notes[0].Freq, want)
+	}
+}
+
+func TestParseRTTTLMalformed(t *testing.T) {
+	if _, err := go_ph0n3.ParseRTTTL("missing-parts"); err == nil {
+		t.Error("expected an error for a string with no ':' separators")
+	}
+	if _, err := go_ph0n3.ParseRTTTL("test:d=4,o=5,b=63:notanote"); err == nil {
+		t.Error("expected an error for an invalid note token")
+	}
+}
+
+// noteFreqForTest mirrors melody.go's unexported noteFrequency (c=0
+// semitones from C, A4=440Hz reference) for "c" only, enough to check
+// ParseRTTTL's octave handling from outside the package.
+func noteFreqForTest(octave int, pitch string) float64 {
+	const cSemitone = 0
+	if pitch != "c" {
+		panic("noteFreqForTest only supports \"c\"")
+	}
+	const a4 = 4*12 + 9
+	halfSteps := octave*12 + cSemitone - a4
+	return 440 * math.Pow(2, float64(halfSteps)/12)
+}
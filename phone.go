@@ -4,8 +4,8 @@
 package go_ph0n3
 
 import (
+	"context"
 	"errors"
-	"github.com/hajimehoshi/oto"
 	"io"
 	"log"
 	"math"
@@ -21,25 +21,52 @@ const (
 
 // ============================================================================
 // sineWave Because we need to play a sound...
-// This is like a "single wave synth"
+// It synthesizes a ToneSegment: its SumFreqs are mixed together and, when
+// ModFreqs is non-empty, the mix is amplitude-modulated by them, which is
+// how tone packs express "f1+f2" and "f1*f2" segments.
 type sineWave struct {
-	freq       float64
+	segment    ToneSegment
 	length     int64
 	pos        int64
 	remaining  []byte
 	channelNum int
+	sampleRate int
 }
 
-func newSineWave(freq float64, duration time.Duration, channelNum int) *sineWave {
-	l := int64(channelNum) * bitDepthInBytes * sampleRate * int64(duration) / int64(time.Second)
+func newSineWave(segment ToneSegment, duration time.Duration, channelNum, sampleRate int) *sineWave {
+	l := int64(channelNum) * bitDepthInBytes * int64(sampleRate) * int64(duration) / int64(time.Second)
 	l = l / 4 * 4
 	return &sineWave{
-		freq:       freq,
+		segment:    segment,
 		length:     l,
 		channelNum: channelNum,
+		sampleRate: sampleRate,
 	}
 }
 
+// amplitudeAt Returns the waveform amplitude, in [-1, 1], at sample index p.
+// A segment with neither SumFreqs nor ModFreqs is silence and is always 0.
+func (s *sineWave) amplitudeAt(p int64) float64 {
+	if s.segment.silent() {
+		return 0
+	}
+
+	t := float64(p) / float64(s.sampleRate)
+
+	amp := 1.0
+	if len(s.segment.SumFreqs) > 0 {
+		amp = 0
+		for _, f := range s.segment.SumFreqs {
+			amp += math.Sin(2 * math.Pi * f * t)
+		}
+		amp /= float64(len(s.segment.SumFreqs))
+	}
+	for _, f := range s.segment.ModFreqs {
+		amp *= math.Sin(2 * math.Pi * f * t)
+	}
+	return amp
+}
+
 func (s *sineWave) Read(buf []byte) (int, error) {
 	if len(s.remaining) > 0 {
 		n := copy(buf, s.remaining)
@@ -63,15 +90,13 @@ func (s *sineWave) Read(buf []byte) (int, error) {
 		buf = make([]byte, len(origBuf)+4-len(origBuf)%4)
 	}
 
-	length := float64(sampleRate) / float64(s.freq)
-
 	num := bitDepthInBytes * s.channelNum
 	p := s.pos / int64(num)
 	switch bitDepthInBytes {
 	case 1:
 		for i := 0; i < len(buf)/num; i++ {
 			const max = 127
-			b := int(math.Sin(2*math.Pi*float64(p)/length) * 0.2 * max)
+			b := int(s.amplitudeAt(p) * 0.2 * max)
 			for ch := 0; ch < s.channelNum; ch++ {
 				buf[num*i+ch] = byte(b + 128)
 			}
@@ -80,7 +105,7 @@ func (s *sineWave) Read(buf []byte) (int, error) {
 	case 2:
 		for i := 0; i < len(buf)/num; i++ {
 			const max = 32767
-			b := int16(math.Sin(2*math.Pi*float64(p)/length) * 0.2 * max)
+			b := int16(s.amplitudeAt(p) * 0.2 * max)
 			for ch := 0; ch < s.channelNum; ch++ {
 				buf[num*i+2*ch] = byte(b)
 				buf[num*i+1+2*ch] = byte(b >> 8)
@@ -122,6 +147,10 @@ type Ph0n3Options struct {
 	Channel int
 	// BuffSizeBytes is the buffer size in bytes
 	BuffSizeBytes int
+	// ToneLang Region whose call-progress tones (dial, ring, busy, ...)
+	// should be used, e.g. "us", "uk", "de", "jp". Defaults to "us" when
+	// empty or unknown. See LoadTonePack to add more regions.
+	ToneLang string `json:"tone_lang"`
 }
 
 // DefaultPh0n3Options the default values.
@@ -131,6 +160,7 @@ var DefaultPh0n3Options = &Ph0n3Options{
 	ToneDuration:     time.Second / 4,
 	BuffSizeBytes:    4096,
 	Channel:          1,
+	ToneLang:         "us",
 }
 
 // ============================================================================
@@ -210,164 +240,312 @@ var fqMapRows = []float64{697, 770, 852, 941}
 // Ph0n3 Is a phone toy you can use to dial a number; it also could be used as
 // dialing tone generator.
 type Ph0n3 struct {
-	opt           *Ph0n3Options
-	ctx           *oto.Context
-	isOpen        bool
+	opt      *Ph0n3Options
+	sink     AudioSink
+	tonePack TonePack
+	ringback []ToneSegment
+
+	mu            sync.Mutex
+	state         Ph0n3State
 	lastEventTime time.Time
 	dialed        string
-	Close         chan bool
+
+	// sinkMu Serializes play() calls end to end, not just individual
+	// sink.Write calls: Dial (on its own goroutine) and dialing/playCadence
+	// (on Open's watchdog goroutine) can otherwise run concurrently and
+	// interleave two unrelated tones' chunks into the same sink mid-stream.
+	sinkMu sync.Mutex
+
+	events     chan Ph0n3Event
+	done       chan struct{}
+	hangupOnce sync.Once
+	cancel     context.CancelFunc
 }
 
-// NewPh0n3 Returns a new phone instance ready to use
+// NewPh0n3 Returns a new phone instance that plays its tones live on the
+// system's default audio device.
 func NewPh0n3(opt *Ph0n3Options) *Ph0n3 {
+	o := opt
+	if o == nil {
+		o = DefaultPh0n3Options
+	}
+
+	sink, err := NewOtoSink(sampleRate, o.Channel, o.BuffSizeBytes)
+	if err != nil {
+		panic(err)
+	}
+
+	return newPh0n3(opt, sink)
+}
+
+// NewPh0n3WithSink Returns a new phone instance that writes its tones into
+// sink instead of opening an audio device, e.g. a WavFileSink to render a
+// dialed sequence to a file for tests, CI, or other headless use.
+func NewPh0n3WithSink(opt *Ph0n3Options, sink AudioSink) *Ph0n3 {
+	return newPh0n3(opt, sink)
+}
+
+func newPh0n3(opt *Ph0n3Options, sink AudioSink) *Ph0n3 {
 	p := new(Ph0n3)
-	p.Close = make(chan bool, 1)
+	p.done = make(chan struct{})
+	p.events = make(chan Ph0n3Event, 32)
 
 	p.opt = opt
 	if opt == nil {
 		p.opt = DefaultPh0n3Options
 	}
 
-	c, err := oto.NewContext(int(sampleRate), p.opt.Channel, bitDepthInBytes, p.opt.BuffSizeBytes)
-	if err != nil {
-		panic(err)
-	}
-
-	p.ctx = c
+	p.sink = sink
+	p.tonePack = activeTonePack(p.opt.ToneLang)
 
 	p.lastEventTime = time.Now()
 	p.dialed = ""
 	return p
 }
 
-// Plays The a sin wave with frequency of <freq> during <duration> time, then
-// wg.Done()on <wg> wait group.
-func (phone *Ph0n3) play(freq float64, duration time.Duration, wg *sync.WaitGroup) {
+// State Returns what point of the call lifecycle phone is currently at.
+func (phone *Ph0n3) State() Ph0n3State {
+	phone.mu.Lock()
+	defer phone.mu.Unlock()
+	return phone.state
+}
+
+func (phone *Ph0n3) setState(s Ph0n3State) {
+	phone.mu.Lock()
+	phone.state = s
+	phone.mu.Unlock()
+}
+
+// Events Returns the channel Ph0n3Event values are delivered on as the call
+// progresses. Sends never block the call itself: if nothing is draining
+// this channel, events are dropped rather than stalling dialing/ringing/etc.
+func (phone *Ph0n3) Events() <-chan Ph0n3Event {
+	return phone.events
+}
+
+func (phone *Ph0n3) emit(ev Ph0n3Event) {
+	select {
+	case phone.events <- ev:
+	default:
+	}
+}
+
+// Done Returns a channel that's closed once the call has ended, whether
+// because it ran its course, Hangup was called, or the Context passed to
+// Open was canceled.
+func (phone *Ph0n3) Done() <-chan struct{} {
+	return phone.done
+}
+
+// Hangup Ends the call, if it hasn't ended already. Safe to call multiple
+// times and from multiple goroutines; only the first call has any effect.
+func (phone *Ph0n3) Hangup() {
+	phone.hangupOnce.Do(func() {
+		phone.setState(StateClosed)
+		phone.mu.Lock()
+		cancel := phone.cancel
+		phone.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+		phone.emit(Closed{})
+		close(phone.done)
+	})
+}
+
+func (phone *Ph0n3) touch() {
+	phone.mu.Lock()
+	phone.lastEventTime = time.Now()
+	phone.mu.Unlock()
+}
+
+func (phone *Ph0n3) idleSince() time.Duration {
+	phone.mu.Lock()
+	defer phone.mu.Unlock()
+	return time.Since(phone.lastEventTime)
+}
+
+func (phone *Ph0n3) dialedString() string {
+	phone.mu.Lock()
+	defer phone.mu.Unlock()
+	return phone.dialed
+}
+
+// ringbackCadence Returns the cadence SetRingback last set, or nil if it was
+// never called, guarded the same way dialedString guards phone.dialed.
+func (phone *Ph0n3) ringbackCadence() []ToneSegment {
+	phone.mu.Lock()
+	defer phone.mu.Unlock()
+	return phone.ringback
+}
+
+// playSilence Writes duration worth of zero-valued samples to the sink, the
+// same way play renders a silent ToneSegment. Used for the gaps between
+// dialed digits and the rests in a melody, so a file sink actually encodes
+// them instead of the call just stalling past them.
+func (phone *Ph0n3) playSilence(duration time.Duration) {
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go phone.play(ToneSegment{}, duration, wg)
+	wg.Wait()
+}
+
+// Plays The segment's tone(s) during <duration> time, then wg.Done() on
+// <wg> wait group. Takes sinkMu for its whole run, not just around each
+// Write, so a concurrent play() (Dial racing the ringing/busy cadence off
+// Open's watchdog, say) can't interleave its chunks into the same stream.
+func (phone *Ph0n3) play(segment ToneSegment, duration time.Duration, wg *sync.WaitGroup) {
 	defer func() {
 		if wg != nil {
 			wg.Done()
 		}
 	}()
-	p := phone.ctx.NewPlayer()
-	s := newSineWave(freq, duration, phone.opt.Channel)
-	if _, err := io.Copy(p, s); err != nil {
-		log.Printf("%v", err)
-		return
+	phone.sinkMu.Lock()
+	defer phone.sinkMu.Unlock()
+
+	s := newSineWave(segment, duration, phone.sink.Channels(), phone.sink.SampleRate())
+	buf := make([]byte, phone.opt.BuffSizeBytes/2*2)
+	for {
+		n, err := s.Read(buf)
+		if n > 0 {
+			if werr := phone.sink.Write(bytesToInt16(buf[:n])); werr != nil {
+				log.Printf("%v", werr)
+				return
+			}
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Printf("%v", err)
+			return
+		}
 	}
-	if err := p.Close(); err != nil {
-		log.Printf("%v", err)
-		return
+}
+
+// playCadence Plays a tone's segments back to back, one full pass through
+// segments. Silent segments (see ToneSegment.silent) go through play like
+// any other, rendering the gap as zero-valued samples instead of just
+// stalling for it, so a file sink actually encodes the silence. Segments
+// with Once are skipped once iteration is greater than 0, so a cadence
+// played on a loop can carry a lead-in that doesn't repeat. defaultDuration
+// is used for segments that don't specify one of their own.
+func (phone *Ph0n3) playCadence(segments []ToneSegment, iteration int, defaultDuration time.Duration) {
+	for _, seg := range segments {
+		if seg.Once && iteration > 0 {
+			continue
+		}
+
+		d := seg.Duration
+		if d == 0 {
+			d = defaultDuration
+		}
+
+		// Silent segments go through play like any other: amplitudeAt
+		// renders them as zero-valued samples, so the sink (a WAV/AIFF
+		// file in particular) actually encodes the gap instead of the
+		// call just stalling for it.
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		go phone.play(seg, d, wg)
+		wg.Wait()
 	}
-	return
 }
 
-func (phone *Ph0n3) dialing() {
+func (phone *Ph0n3) dialing(ctx context.Context) {
+	phone.setState(StateRinging)
 	if phone.opt.RingingToneTimes > 0 {
-		for i := 0; i < 3; i++ {
-			wg := new(sync.WaitGroup)
-			wg.Add(2)
-			go phone.play(480, time.Second*2, wg)
-			go phone.play(440, time.Second*2, wg)
-			wg.Wait()
-			time.Sleep(time.Second * 4)
+		cadence := phone.tone("ring")
+		if rb := phone.ringbackCadence(); rb != nil {
+			cadence = rb
+		}
+		phone.emit(Ringing{})
+		for i := 0; i < phone.opt.RingingToneTimes && ctx.Err() == nil; i++ {
+			phone.playCadence(cadence, i, phone.opt.ToneDuration)
 		}
 	}
 
-	phone.endingCall()
+	phone.endingCall(ctx)
 }
 
-func (phone *Ph0n3) endingCall() {
+func (phone *Ph0n3) endingCall(ctx context.Context) {
 	if phone.opt.BusyToneTimes < 0 {
-		if phone.dialed == strings.Repeat("5", 5) {
-			var f, t float64
-			for i, v := range []float64{0.055, 233.8, 4, 311.13, 2, 369.99, 4, 415.3,
-				2, 440, 4, 466.6, 2, 440, 4, 415.3, 2, 369.99, 6, 233.8, 6, 277.18, 6, 311.13, 13} {
-				if i == 0 {
-					t = v
-					continue
-				}
-				if (i+3)%2 == 1 {
-					phone.play(f, time.Duration(t*v*1E9), nil)
-				} else {
-					f = v
-				}
-			}
+		if phone.dialedString() == strings.Repeat("5", 5) {
+			phone.playNotes(easterEggMelody)
 		}
 
-		for i := 0; i < phone.opt.BusyToneTimes; i++ {
-			wg := new(sync.WaitGroup)
-			wg.Add(2)
-			go phone.play(480, time.Second/4, wg)
-			go phone.play(620, time.Second/4, wg)
-			wg.Wait()
-			time.Sleep(time.Second / 4)
+		phone.setState(StateBusy)
+		cadence := phone.tone("busy")
+		phone.emit(Busy{})
+		for i := 0; i < phone.opt.BusyToneTimes && ctx.Err() == nil; i++ {
+			phone.playCadence(cadence, i, phone.opt.ToneDuration)
 		}
 	}
-	phone.isOpen = false
-	phone.Close <- true
+	phone.Hangup()
 }
 
-// Open Opens the line with a dial tone
-func (phone *Ph0n3) Open() *Ph0n3 {
-	if phone.isOpen {
+// Open Opens the line with a dial tone. ctx can be canceled to hang up an
+// in-progress call early; use context.Background() if that's never needed.
+func (phone *Ph0n3) Open(ctx context.Context) *Ph0n3 {
+	phone.mu.Lock()
+	if phone.state != StateIdle {
+		phone.mu.Unlock()
 		return phone
 	}
+	phone.state = StateOpen
 	phone.lastEventTime = time.Now()
-	phone.isOpen = true
+	phone.mu.Unlock()
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(ctx)
+	phone.mu.Lock()
+	phone.cancel = cancel
+	phone.mu.Unlock()
 
 	if phone.opt.DialToneDuration > 0 {
-		wg := new(sync.WaitGroup)
-		wg.Add(2)
-		go phone.play(480, time.Second*2, wg)
-		go phone.play(620, time.Second*2, wg)
-		wg.Wait()
-		time.Sleep(time.Second / 4)
+		phone.emit(DialToneStarted{})
+		phone.playCadence(phone.tone("dial"), 0, phone.opt.DialToneDuration)
 	}
 
 	go func() {
-		// Waiting for no events during 3s to do the call
-		for time.Since(phone.lastEventTime) < (3 * time.Second) {
-			time.Sleep(time.Second / 2)
+		ticker := time.NewTicker(time.Second / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				phone.Hangup()
+				return
+			case <-ticker.C:
+				// Waiting for no events during 3s to do the call
+				if phone.idleSince() >= 3*time.Second {
+					phone.dialing(ctx)
+					return
+				}
+			}
 		}
-
-		phone.dialing()
 	}()
 	return phone
 }
 
 // Dial Dials a key sequence
 func (phone *Ph0n3) Dial(keys ...Ph0n3Key) error {
-	defer func() {
-		phone.lastEventTime = time.Now()
-	}()
+	defer phone.touch()
+
 	var wg *sync.WaitGroup
 	for _, k := range keys {
-		switch k {
-		case Key0:
-			phone.dialed += "0"
-		case Key1:
-			phone.dialed += "1"
-		case Key2:
-			phone.dialed += "2"
-		case Key3:
-			phone.dialed += "3"
-		case Key4:
-			phone.dialed += "4"
-		case Key5:
-			phone.dialed += "5"
-		case Key6:
-			phone.dialed += "6"
-		case Key7:
-			phone.dialed += "7"
-		case Key8:
-			phone.dialed += "8"
-		case Key9:
-			phone.dialed += "9"
-		case KeyStar:
-			phone.dialed += "*"
-		case KeyHash:
-			phone.dialed += "#"
+		// touch per key, not just once via the deferred call above, so the
+		// Open watchdog can't decide the line went idle and start dialing()
+		// while a multi-key Dial call is still in progress.
+		phone.touch()
+
+		digit, ok := keyDigits[k]
+		if ok {
+			phone.mu.Lock()
+			phone.dialed += digit
+			phone.mu.Unlock()
 		}
+
 		row := int(k) / len(fqMapRows)
 		if row > len(fqMapRows) {
 			return errors.New("value out of range")
@@ -379,15 +557,33 @@ func (phone *Ph0n3) Dial(keys ...Ph0n3Key) error {
 		}
 
 		wg = new(sync.WaitGroup)
-		wg.Add(2)
-		go phone.play(fqMapCols[col], phone.opt.ToneDuration, wg)
-		go phone.play(fqMapRows[row], phone.opt.ToneDuration, wg)
+		wg.Add(1)
+		go phone.play(ToneSegment{SumFreqs: []float64{fqMapCols[col], fqMapRows[row]}}, phone.opt.ToneDuration, wg)
 		wg.Wait()
-		time.Sleep(phone.opt.SpaceDuration)
+		phone.emit(KeyPressed{Key: k, At: time.Now()})
+		phone.playSilence(phone.opt.SpaceDuration)
 	}
 	return nil
 }
 
+// keyDigits Maps a key to the digit DialString's StandarPad reverses;
+// Dial uses it to build up the dialed string the "55555" easter egg and
+// future key-sequence features check against.
+var keyDigits = map[Ph0n3Key]string{
+	Key0:    "0",
+	Key1:    "1",
+	Key2:    "2",
+	Key3:    "3",
+	Key4:    "4",
+	Key5:    "5",
+	Key6:    "6",
+	Key7:    "7",
+	Key8:    "8",
+	Key9:    "9",
+	KeyStar: "*",
+	KeyHash: "#",
+}
+
 // DialString Dial keys from the given strings, if a char does not exists it
 // skips and continue with next.
 func (phone *Ph0n3) DialString(text string) error {
@@ -0,0 +1,46 @@
+package go_ph0n3
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestAmplitudeAtSum checks amplitudeAt's additive ("+") mixing: the mean of
+// the two constituent sine waves.
+func TestAmplitudeAtSum(t *testing.T) {
+	const rate = 8000
+	s := newSineWave(ToneSegment{SumFreqs: []float64{350, 440}}, time.Second, 1, rate)
+
+	const p = 17
+	tt := float64(p) / float64(rate)
+	want := (math.Sin(2*math.Pi*350*tt) + math.Sin(2*math.Pi*440*tt)) / 2
+
+	if got := s.amplitudeAt(p); math.Abs(got-want) > 1e-9 {
+		t.Errorf("amplitudeAt(%d) = %v, want %v", p, got, want)
+	}
+}
+
+// TestAmplitudeAtModulation checks amplitudeAt's AM-modulation ("*")
+// behavior: the carrier ("+") amplitude multiplied by each modulator.
+func TestAmplitudeAtModulation(t *testing.T) {
+	const rate = 8000
+	s := newSineWave(ToneSegment{SumFreqs: []float64{440}, ModFreqs: []float64{16}}, time.Second, 1, rate)
+
+	const p = 17
+	tt := float64(p) / float64(rate)
+	want := math.Sin(2*math.Pi*440*tt) * math.Sin(2*math.Pi*16*tt)
+
+	if got := s.amplitudeAt(p); math.Abs(got-want) > 1e-9 {
+		t.Errorf("amplitudeAt(%d) = %v, want %v", p, got, want)
+	}
+}
+
+// TestAmplitudeAtSilence checks a segment with no frequencies at all is
+// always zero, the case play relies on to render real silence to the sink.
+func TestAmplitudeAtSilence(t *testing.T) {
+	s := newSineWave(ToneSegment{}, time.Second, 1, 8000)
+	if got := s.amplitudeAt(17); got != 0 {
+		t.Errorf("amplitudeAt(17) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,68 @@
+package go_ph0n3_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	go_ph0n3 "github.com/umarquez/go-ph0n3"
+)
+
+// TestOpenHangupConcurrent dials Open and Hangup from different goroutines,
+// an ordinary pattern (e.g. a caller hanging up right after placing the
+// call), and makes sure phone.cancel is never read or written unguarded.
+// Run with -race to catch it.
+func TestOpenHangupConcurrent(t *testing.T) {
+	phone := go_ph0n3.NewPh0n3(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		phone.Open(context.Background())
+	}()
+	go func() {
+		defer wg.Done()
+		phone.Hangup()
+	}()
+	wg.Wait()
+
+	phone.Hangup()
+	<-phone.Done()
+}
+
+// TestDialConcurrent dials two key sequences on the same phone from
+// different goroutines, the shape of the race between Dial and the Open
+// watchdog's dialing/playCadence: both ultimately call play(), which writes
+// into the same shared sink. A WavFileSink is used (not a no-op sink)
+// because it has real mutable state (bytesWritten) for two interleaved
+// streams to corrupt; run with -race to confirm the writes are serialized
+// instead of racing on it.
+func TestDialConcurrent(t *testing.T) {
+	f, err := os.Create(t.TempDir() + "/concurrent.wav")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer f.Close()
+
+	sink, err := go_ph0n3.NewWavFileSink(f, 8000, 1)
+	if err != nil {
+		t.Fatalf("NewWavFileSink: %v", err)
+	}
+	defer sink.Close()
+
+	phone := go_ph0n3.NewPh0n3WithSink(nil, sink)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_ = phone.Dial(go_ph0n3.Key1, go_ph0n3.Key2, go_ph0n3.Key3)
+	}()
+	go func() {
+		defer wg.Done()
+		_ = phone.Dial(go_ph0n3.Key4, go_ph0n3.Key5, go_ph0n3.Key6)
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,263 @@
+package go_ph0n3
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/oto"
+)
+
+// ============================================================================
+// AudioSink Is where a Ph0n3 instance's tones end up. OtoSink plays them on
+// the default audio device in real time; WavFileSink and AiffFileSink render
+// them to a file instead, which is handy for tests, CI, or any headless
+// environment without an audio device.
+type AudioSink interface {
+	// Write Consumes a batch of interleaved samples, one per channel per
+	// frame, in the order given by Channels.
+	Write(samples []int16) error
+	// SampleRate Rate, in Hz, samples are expected at.
+	SampleRate() int
+	// Channels Number of interleaved channels each Write call carries.
+	Channels() int
+	// Close Releases whatever resource backs the sink. For file sinks this
+	// is where the header gets patched with its final sizes.
+	Close() error
+}
+
+// int16ToBytes Packs samples as little-endian 16-bit PCM, the wire format
+// both Oto and WavFileSink below use (AiffFileSink writes big-endian).
+func int16ToBytes(samples []int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(s))
+	}
+	return buf
+}
+
+// bytesToInt16 Is the inverse of int16ToBytes: it unpacks little-endian
+// 16-bit PCM, which is what sineWave.Read produces, into the samples an
+// AudioSink expects.
+func bytesToInt16(buf []byte) []int16 {
+	samples := make([]int16, len(buf)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(buf[i*2:]))
+	}
+	return samples
+}
+
+// ============================================================================
+// OtoSink Plays samples on the system's default audio device through Oto, as
+// Ph0n3 always did before AudioSink existed.
+type OtoSink struct {
+	ctx        *oto.Context
+	player     *oto.Player
+	sampleRate int
+	channels   int
+}
+
+// NewOtoSink Opens the default audio device for real-time playback.
+func NewOtoSink(sampleRate, channels, buffSizeBytes int) (*OtoSink, error) {
+	ctx, err := oto.NewContext(sampleRate, channels, bitDepthInBytes, buffSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &OtoSink{
+		ctx:        ctx,
+		player:     ctx.NewPlayer(),
+		sampleRate: sampleRate,
+		channels:   channels,
+	}, nil
+}
+
+func (o *OtoSink) Write(samples []int16) error {
+	_, err := o.player.Write(int16ToBytes(samples))
+	return err
+}
+
+func (o *OtoSink) SampleRate() int { return o.sampleRate }
+func (o *OtoSink) Channels() int   { return o.channels }
+func (o *OtoSink) Close() error    { return o.player.Close() }
+
+// ============================================================================
+// fileSink Carries what WavFileSink and AiffFileSink have in common: they
+// both stream a header followed by samples to an io.WriteSeeker and patch
+// the header's size fields once the final length is known, at Close.
+type fileSink struct {
+	w            io.WriteSeeker
+	sampleRate   int
+	channels     int
+	bytesWritten int64
+}
+
+// WavFileSink Renders samples as a RIFF/WAVE file: a `RIFF` header, a `fmt `
+// chunk describing 16-bit little-endian PCM, and a `data` chunk holding the
+// interleaved samples. The RIFF and data chunk sizes are placeholders until
+// Close, when they're patched with the real sample count.
+type WavFileSink struct {
+	fileSink
+}
+
+// NewWavFileSink Writes a provisional WAVE header to w and returns a sink
+// ready to stream samples to it. w must support Seek so Close can patch the
+// header's size fields.
+func NewWavFileSink(w io.WriteSeeker, sampleRate, channels int) (*WavFileSink, error) {
+	s := &WavFileSink{fileSink{w: w, sampleRate: sampleRate, channels: channels}}
+	if err := s.writeHeader(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *WavFileSink) writeHeader() error {
+	byteRate := s.sampleRate * s.channels * bitDepthInBytes
+	blockAlign := s.channels * bitDepthInBytes
+
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], 36) // patched at Close
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16) // fmt chunk size
+	binary.LittleEndian.PutUint16(header[20:22], 1)  // PCM
+	binary.LittleEndian.PutUint16(header[22:24], uint16(s.channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(s.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(byteRate))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(bitDepthInBytes*8))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], 0) // patched at Close
+
+	_, err := s.w.Write(header)
+	return err
+}
+
+func (s *WavFileSink) Write(samples []int16) error {
+	buf := int16ToBytes(samples)
+	if _, err := s.w.Write(buf); err != nil {
+		return err
+	}
+	s.bytesWritten += int64(len(buf))
+	return nil
+}
+
+func (s *WavFileSink) SampleRate() int { return s.sampleRate }
+func (s *WavFileSink) Channels() int   { return s.channels }
+
+func (s *WavFileSink) Close() error {
+	if _, err := s.w.Seek(4, io.SeekStart); err != nil {
+		return fmt.Errorf("go-ph0n3: patching RIFF size: %w", err)
+	}
+	if err := binary.Write(s.w, binary.LittleEndian, uint32(36+s.bytesWritten)); err != nil {
+		return err
+	}
+	if _, err := s.w.Seek(40, io.SeekStart); err != nil {
+		return fmt.Errorf("go-ph0n3: patching data size: %w", err)
+	}
+	return binary.Write(s.w, binary.LittleEndian, uint32(s.bytesWritten))
+}
+
+// AiffFileSink Renders samples as a big-endian AIFF file: a `FORM`/`AIFF`
+// header, a `COMM` chunk describing 16-bit PCM, and an `SSND` chunk holding
+// the interleaved samples. The FORM and SSND chunk sizes are placeholders
+// until Close.
+type AiffFileSink struct {
+	fileSink
+}
+
+// NewAiffFileSink Writes a provisional AIFF header to w and returns a sink
+// ready to stream samples to it. w must support Seek so Close can patch the
+// header's size fields.
+func NewAiffFileSink(w io.WriteSeeker, sampleRate, channels int) (*AiffFileSink, error) {
+	s := &AiffFileSink{fileSink{w: w, sampleRate: sampleRate, channels: channels}}
+	if err := s.writeHeader(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// aiffExtended80 Encodes sampleRate as an 80-bit IEEE 754 extended float,
+// the format AIFF's COMM chunk requires for its sample rate field.
+func aiffExtended80(sampleRate int) [10]byte {
+	var out [10]byte
+	if sampleRate == 0 {
+		return out
+	}
+
+	exponent := uint16(16383 + 31)
+	mantissa := uint64(sampleRate) << 32
+	for mantissa&(1<<63) == 0 {
+		mantissa <<= 1
+		exponent--
+	}
+
+	binary.BigEndian.PutUint16(out[0:2], exponent)
+	binary.BigEndian.PutUint64(out[2:10], mantissa)
+	return out
+}
+
+func (s *AiffFileSink) writeHeader() error {
+	header := make([]byte, 12+26) // FORM/AIFF + COMM chunk, SSND written separately
+	copy(header[0:4], "FORM")
+	binary.BigEndian.PutUint32(header[4:8], 0) // patched at Close
+	copy(header[8:12], "AIFF")
+	copy(header[12:16], "COMM")
+	binary.BigEndian.PutUint32(header[16:20], 18) // COMM chunk size
+	binary.BigEndian.PutUint16(header[20:22], uint16(s.channels))
+	binary.BigEndian.PutUint32(header[22:26], 0) // numSampleFrames, patched at Close
+	binary.BigEndian.PutUint16(header[26:28], uint16(bitDepthInBytes*8))
+	rate := aiffExtended80(s.sampleRate)
+	copy(header[28:38], rate[:])
+
+	if _, err := s.w.Write(header); err != nil {
+		return err
+	}
+
+	ssndHeader := make([]byte, 16)
+	copy(ssndHeader[0:4], "SSND")
+	binary.BigEndian.PutUint32(ssndHeader[4:8], 0)   // patched at Close
+	binary.BigEndian.PutUint32(ssndHeader[8:12], 0)  // offset
+	binary.BigEndian.PutUint32(ssndHeader[12:16], 0) // blockSize
+	_, err := s.w.Write(ssndHeader)
+	return err
+}
+
+func (s *AiffFileSink) Write(samples []int16) error {
+	buf := make([]byte, len(samples)*2)
+	for i, smp := range samples {
+		binary.BigEndian.PutUint16(buf[i*2:], uint16(smp))
+	}
+	if _, err := s.w.Write(buf); err != nil {
+		return err
+	}
+	s.bytesWritten += int64(len(buf))
+	return nil
+}
+
+func (s *AiffFileSink) SampleRate() int { return s.sampleRate }
+func (s *AiffFileSink) Channels() int   { return s.channels }
+
+func (s *AiffFileSink) Close() error {
+	numSampleFrames := s.bytesWritten / int64(s.channels*bitDepthInBytes)
+
+	if _, err := s.w.Seek(4, io.SeekStart); err != nil {
+		return fmt.Errorf("go-ph0n3: patching FORM size: %w", err)
+	}
+	formSize := uint32(4 + 8 + 18 + 8 + 8 + s.bytesWritten) // "AIFF" + COMM header+body + SSND header+body
+	if err := binary.Write(s.w, binary.BigEndian, formSize); err != nil {
+		return err
+	}
+
+	if _, err := s.w.Seek(22, io.SeekStart); err != nil {
+		return fmt.Errorf("go-ph0n3: patching numSampleFrames: %w", err)
+	}
+	if err := binary.Write(s.w, binary.BigEndian, uint32(numSampleFrames)); err != nil {
+		return err
+	}
+
+	if _, err := s.w.Seek(42, io.SeekStart); err != nil {
+		return fmt.Errorf("go-ph0n3: patching SSND size: %w", err)
+	}
+	return binary.Write(s.w, binary.BigEndian, uint32(8+s.bytesWritten))
+}
@@ -0,0 +1,269 @@
+package go_ph0n3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// ToneSegment Is a single piece of a call-progress tone, modeled after the
+// segment grammar used by Asterisk/YATE's indications.conf: a segment is
+// either silence or one or more frequencies combined by addition (a mixed
+// tone, e.g. "350+440") or multiplication (an AM-modulated tone, e.g.
+// "1400*425"), each segment lasting for Duration before the next one in the
+// tone's cadence starts.
+type ToneSegment struct {
+	// SumFreqs Frequencies mixed together (the "+" operator). Empty when the
+	// segment is silence.
+	SumFreqs []float64
+	// ModFreqs Frequencies the (SumFreqs) signal is amplitude-modulated by
+	// (the "*" operator).
+	ModFreqs []float64
+	// Duration How long this segment plays before the cadence moves on.
+	Duration time.Duration
+	// Once When true, this segment is only played on the first pass through
+	// the cadence, not on repeats.
+	Once bool
+}
+
+// silent Reports whether this segment carries no frequency at all, i.e. it
+// is the gap between tones rather than a tone itself.
+func (s ToneSegment) silent() bool {
+	return len(s.SumFreqs) == 0 && len(s.ModFreqs) == 0
+}
+
+// TonePack Maps a call-progress tone name (dial, ring, busy, congestion,
+// reorder, confirmation, ...) to the cadence of segments that makes it up.
+type TonePack map[string][]ToneSegment
+
+// tonePacksMu Guards tonePacks: it's a package global that LoadTonePack can
+// mutate from any Ph0n3 instance's goroutine, while every NewPh0n3(WithSink)
+// call reads it to resolve its initial pack, so both sides need a lock.
+var tonePacksMu sync.RWMutex
+
+// tonePacks Holds the registered tone packs, keyed by region/language (us,
+// uk, de, jp, ...). New packs can be added at runtime through LoadTonePack.
+// Guarded by tonePacksMu.
+var tonePacks = map[string]TonePack{
+	"us": {
+		"dial":         parseTone("350+440"),
+		"ring":         parseTone("440+480/2000,0/4000"),
+		"busy":         parseTone("480+620/500,0/500"),
+		"congestion":   parseTone("480+620/250,0/250"),
+		"reorder":      parseTone("480+620/250,0/250"),
+		"confirmation": parseTone("350+440/100,0/100"),
+	},
+	"uk": {
+		"dial":         parseTone("350+440"),
+		"ring":         parseTone("400+450/400,0/200,400+450/400,0/2000"),
+		"busy":         parseTone("400/375,0/375"),
+		"congestion":   parseTone("400/400,0/350,400/225,0/525"),
+		"reorder":      parseTone("400/400,0/350,400/225,0/525"),
+		"confirmation": parseTone("400+450/100,0/100"),
+	},
+	"de": {
+		"dial":         parseTone("425"),
+		"ring":         parseTone("425/1000,0/4000"),
+		"busy":         parseTone("425/480,0/480"),
+		"congestion":   parseTone("425/240,0/240"),
+		"reorder":      parseTone("425/240,0/240"),
+		"confirmation": parseTone("425/100,0/100"),
+	},
+	"jp": {
+		"dial":         parseTone("400"),
+		"ring":         parseTone("400+16/1000,0/2000"),
+		"busy":         parseTone("400/500,0/500"),
+		"congestion":   parseTone("400/500,0/500"),
+		"reorder":      parseTone("400/500,0/500"),
+		"confirmation": parseTone("400+16/100,0/100"),
+	},
+}
+
+// parseTone Parses a comma-separated list of segments, as documented on
+// ToneSegment, panicking on malformed built-in packs so mistakes are caught
+// at init time rather than at play time. User-supplied packs go through
+// parseToneSegments instead, which returns the error to the caller.
+func parseTone(spec string) []ToneSegment {
+	segs, err := parseToneSegments(spec)
+	if err != nil {
+		panic(fmt.Sprintf("go-ph0n3: invalid built-in tone %q: %v", spec, err))
+	}
+	return segs
+}
+
+// parseToneSegments Parses a comma-separated list of "[!]freq[/durationMs]"
+// segments into their ToneSegment representation.
+func parseToneSegments(spec string) ([]ToneSegment, error) {
+	var segments []ToneSegment
+	for _, raw := range strings.Split(spec, ",") {
+		seg, err := parseToneSegment(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// parseToneSegment Parses a single "[!]freq[/durationMs]" segment, where
+// freq is "0" for silence, a single frequency, a sum "f1+f2+..." or a
+// product "f1*f2*...".
+func parseToneSegment(raw string) (ToneSegment, error) {
+	var seg ToneSegment
+
+	if strings.HasPrefix(raw, "!") {
+		seg.Once = true
+		raw = raw[1:]
+	}
+
+	freqSpec := raw
+	if idx := strings.IndexByte(raw, '/'); idx >= 0 {
+		freqSpec = raw[:idx]
+		ms, err := strconv.Atoi(raw[idx+1:])
+		if err != nil {
+			return ToneSegment{}, fmt.Errorf("go-ph0n3: invalid segment duration in %q: %w", raw, err)
+		}
+		seg.Duration = time.Duration(ms) * time.Millisecond
+	}
+
+	if freqSpec == "0" {
+		return seg, nil
+	}
+
+	switch {
+	case strings.Contains(freqSpec, "*"):
+		freqs, err := parseFreqs(freqSpec, "*")
+		if err != nil {
+			return ToneSegment{}, err
+		}
+		seg.ModFreqs = freqs
+	case strings.Contains(freqSpec, "+"):
+		freqs, err := parseFreqs(freqSpec, "+")
+		if err != nil {
+			return ToneSegment{}, err
+		}
+		seg.SumFreqs = freqs
+	default:
+		f, err := strconv.ParseFloat(freqSpec, 64)
+		if err != nil {
+			return ToneSegment{}, fmt.Errorf("go-ph0n3: invalid segment frequency %q: %w", freqSpec, err)
+		}
+		seg.SumFreqs = []float64{f}
+	}
+
+	return seg, nil
+}
+
+func parseFreqs(freqSpec, sep string) ([]float64, error) {
+	parts := strings.Split(freqSpec, sep)
+	freqs := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil, fmt.Errorf("go-ph0n3: invalid segment frequency %q: %w", freqSpec, err)
+		}
+		freqs = append(freqs, f)
+	}
+	return freqs, nil
+}
+
+// LoadTonePack Reads a JSON tone-pack config from r and registers it so its
+// regions become selectable through Ph0n3Options.ToneLang. The config shape
+// is a region name mapped to its tones, each tone a comma-separated list of
+// "[!]freq[/durationMs]" segments:
+//
+//	{
+//	  "uk": {
+//	    "dial": "350+440",
+//	    "ring": "400+450/400,0/200,400+450/400,0/2000"
+//	  }
+//	}
+//
+// If phone is already configured for one of the loaded regions, its active
+// pack is refreshed immediately.
+func (phone *Ph0n3) LoadTonePack(r io.Reader) error {
+	var cfg map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return fmt.Errorf("go-ph0n3: decoding tone pack: %w", err)
+	}
+
+	for lang, tones := range cfg {
+		pack := make(TonePack, len(tones))
+		for name, spec := range tones {
+			segments, err := parseToneSegments(spec)
+			if err != nil {
+				return fmt.Errorf("go-ph0n3: tone pack %q, tone %q: %w", lang, name, err)
+			}
+			pack[name] = segments
+		}
+		registerLangPack(lang, pack)
+	}
+
+	active := activeTonePack(phone.opt.ToneLang)
+	phone.mu.Lock()
+	phone.tonePack = active
+	phone.mu.Unlock()
+	return nil
+}
+
+// registerLangPack Merges pack into the global registry under lang, adding
+// to or overriding any tones already registered for that region.
+func registerLangPack(lang string, pack TonePack) {
+	tonePacksMu.Lock()
+	defer tonePacksMu.Unlock()
+
+	existing, ok := tonePacks[lang]
+	if !ok {
+		existing = make(TonePack, len(pack))
+	}
+	for name, segments := range pack {
+		existing[name] = segments
+	}
+	tonePacks[lang] = existing
+}
+
+// activeTonePack Returns a copy of the registered pack for lang, falling
+// back to "us" when lang is empty or unknown.
+func activeTonePack(lang string) TonePack {
+	tonePacksMu.RLock()
+	defer tonePacksMu.RUnlock()
+
+	pack, ok := tonePacks[lang]
+	if !ok {
+		pack = tonePacks["us"]
+	}
+	active := make(TonePack, len(pack))
+	for name, segments := range pack {
+		active[name] = segments
+	}
+	return active
+}
+
+// RegisterTone Overrides the cadence played for the named tone (dial, ring,
+// busy, ...) on this phone instance only; the global packs other instances
+// resolve from are left untouched.
+func (phone *Ph0n3) RegisterTone(name string, segments []ToneSegment) {
+	phone.mu.Lock()
+	phone.tonePack[name] = segments
+	phone.mu.Unlock()
+}
+
+// tone Returns the active cadence for the named tone, falling back to the
+// "us" pack if the active one doesn't define it.
+func (phone *Ph0n3) tone(name string) []ToneSegment {
+	phone.mu.Lock()
+	segments, ok := phone.tonePack[name]
+	phone.mu.Unlock()
+	if ok {
+		return segments
+	}
+
+	tonePacksMu.RLock()
+	defer tonePacksMu.RUnlock()
+	return tonePacks["us"][name]
+}
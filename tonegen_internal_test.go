@@ -0,0 +1,91 @@
+package go_ph0n3
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseToneSegment covers the "[!]freq[/durationMs]" grammar directly:
+// the "!" once-marker, "+" sum and "*" modulation operators, silence, and
+// malformed input.
+func TestParseToneSegment(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    ToneSegment
+		wantErr bool
+	}{
+		{
+			name: "single frequency",
+			raw:  "350",
+			want: ToneSegment{SumFreqs: []float64{350}},
+		},
+		{
+			name: "sum",
+			raw:  "350+440",
+			want: ToneSegment{SumFreqs: []float64{350, 440}},
+		},
+		{
+			name: "modulation",
+			raw:  "440*480",
+			want: ToneSegment{ModFreqs: []float64{440, 480}},
+		},
+		{
+			name: "silence",
+			raw:  "0/4000",
+			want: ToneSegment{Duration: 4000 * time.Millisecond},
+		},
+		{
+			name: "once marker",
+			raw:  "!350+440/100",
+			want: ToneSegment{SumFreqs: []float64{350, 440}, Duration: 100 * time.Millisecond, Once: true},
+		},
+		{
+			name:    "malformed duration",
+			raw:     "350/nope",
+			wantErr: true,
+		},
+		{
+			name:    "malformed frequency",
+			raw:     "nope",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseToneSegment(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseToneSegment(%q): expected an error, got %+v", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseToneSegment(%q): %v", tt.raw, err)
+			}
+			if !segmentsEqual(got, tt.want) {
+				t.Errorf("parseToneSegment(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func segmentsEqual(a, b ToneSegment) bool {
+	if a.Duration != b.Duration || a.Once != b.Once {
+		return false
+	}
+	return freqsEqual(a.SumFreqs, b.SumFreqs) && freqsEqual(a.ModFreqs, b.ModFreqs)
+}
+
+func freqsEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
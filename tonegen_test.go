@@ -0,0 +1,56 @@
+package go_ph0n3_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	go_ph0n3 "github.com/umarquez/go-ph0n3"
+)
+
+// TestLoadTonePackConcurrent loads custom tone packs from one goroutine
+// while others construct new Ph0n3 instances (which resolve the global
+// registry to pick their starting pack), the pattern go test -race caught
+// as a concurrent map read/write on the package-global tonePacks map.
+func TestLoadTonePackConcurrent(t *testing.T) {
+	const cfg = `{"xx": {"dial": "350+440"}}`
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		phone := go_ph0n3.NewPh0n3(nil)
+		if err := phone.LoadTonePack(strings.NewReader(cfg)); err != nil {
+			t.Error(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		go_ph0n3.NewPh0n3(nil)
+	}()
+	wg.Wait()
+}
+
+// TestRegisterToneLoadTonePackConcurrent calls RegisterTone and LoadTonePack
+// on the same live phone from different goroutines, the pattern go test
+// -race caught as a data race on the per-instance phone.tonePack field
+// (distinct from the package-global tonePacks registry TestLoadTonePackConcurrent
+// covers).
+func TestRegisterToneLoadTonePackConcurrent(t *testing.T) {
+	const cfg = `{"us": {"dial": "350+440"}}`
+	phone := go_ph0n3.NewPh0n3(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		phone.RegisterTone("dial", []go_ph0n3.ToneSegment{{SumFreqs: []float64{350, 440}}})
+	}()
+	go func() {
+		defer wg.Done()
+		if err := phone.LoadTonePack(strings.NewReader(cfg)); err != nil {
+			t.Error(err)
+		}
+	}()
+	wg.Wait()
+}